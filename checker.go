@@ -1,15 +1,20 @@
 package main
 
 import (
-	"crypto/tls"
+	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/SirBarksALot/starlight/internal/cache"
+	"github.com/SirBarksALot/starlight/internal/certcheck"
+	"github.com/SirBarksALot/starlight/internal/certreport"
+	"github.com/SirBarksALot/starlight/internal/pool"
 	"github.com/golang/gddo/httputil/header"
 	"io"
 	"log"
 	"net/http"
-	"strconv"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +22,38 @@ import (
 
 const concurrent = 10
 
+// maxDomains bounds how many domains a single /api/checker request may
+// submit, so one request can't tie up the whole worker pool.
+const maxDomains = 500
+
+// cacheTTL is how long a domain's check result is reused before it's
+// considered stale.
+const cacheTTL = time.Hour
+
+// caBundleEnvVar, when set, points at a PEM file of CA certificates that
+// verbose certificate reports are verified against in place of the system
+// root pool (e.g. for probing hosts behind an internal CA).
+const caBundleEnvVar = "STARLIGHT_CA_BUNDLE"
+
+// reportRoots is the CA pool verbose certificate reports are verified
+// against (nil uses the system pool).
+var reportRoots = loadCABundle()
+
+var checkPool = pool.New(concurrent, reportRoots)
+var domainCache = cache.New(cacheTTL, checkPool)
+
+func loadCABundle() *x509.CertPool {
+	path := os.Getenv(caBundleEnvVar)
+	if path == "" {
+		return nil
+	}
+	roots, err := certreport.LoadRootPool(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return roots
+}
+
 type SafeMap struct {
 	v   map[string]string
 	mux sync.Mutex
@@ -36,53 +73,45 @@ func (results *SafeMap) Retrieve(key string) string {
 	return results.v[key]
 }
 
-func checkDomain(domain string) string {
-	var host = domain + ":443"
-	conn, err := tls.Dial("tcp", host, nil)
-	if err != nil {
-		log.Print(err)
-		return err.Error()
-	}
-	defer conn.Close()
-
-	cert := conn.ConnectionState().PeerCertificates[0]
-	timeNow := time.Now()
-	expiresIn := strconv.FormatFloat(cert.NotAfter.Sub(timeNow).Hours()/24, 'f', 0, 64)
-
-	return expiresIn
-}
-
-func processBatch(domains []string, output map[string]string) {
+func processBatch(ctx context.Context, domains []string, output, meta map[string]string, force bool) {
 	var wg sync.WaitGroup
 	var results = SafeMap{v: output}
+	var metaResults = SafeMap{v: meta}
 
 	fmt.Println(domains)
 	for _, domain := range domains {
 		// Increment the WaitGroup counter.
 		wg.Add(1)
-		// Launch a goroutine to check the domain.
-		go func(domain string) {
-			results.Ins(domain, checkDomain(domain))
-			// Decrement the counter when the goroutine completes.
+		ch := domainCache.Submit(domain, force)
+		// Launch a goroutine to wait on this domain's result.
+		go func(domain string, ch <-chan cache.CheckResult) {
 			defer wg.Done()
-		}(domain)
+			select {
+			case cr := <-ch:
+				results.Ins(domain, certcheck.FormatDays(cr.Result))
+				metaResults.Ins(domain, string(cr.Status))
+			case <-ctx.Done():
+				results.Ins(domain, ctx.Err().Error())
+			}
+		}(domain, ch)
 	}
 	// Wait for all domains from batch to complete.
 	wg.Wait()
 	return
 }
 
-func checker(domainsList []string) map[string]string {
+func checker(ctx context.Context, domainsList []string, force bool) (map[string]string, map[string]string) {
 	output := make(map[string]string)
+	meta := make(map[string]string)
 
 	for i := 0; i < len(domainsList); i += concurrent {
 		if i+concurrent > len(domainsList) {
-			processBatch(domainsList[i:], output)
+			processBatch(ctx, domainsList[i:], output, meta, force)
 		} else {
-			processBatch(domainsList[i:i+concurrent], output)
+			processBatch(ctx, domainsList[i:i+concurrent], output, meta, force)
 		}
 	}
-	return output
+	return output, meta
 }
 
 type DomainList struct {
@@ -91,6 +120,7 @@ type DomainList struct {
 
 type respData struct {
 	Data        map[string]string
+	Meta        map[string]string
 	RequestTime string
 }
 
@@ -100,8 +130,7 @@ func handleRequest(w http.ResponseWriter, req *http.Request) {
 	if req.Header.Get("Content-Type") != "" {
 		value, _ := header.ParseValueAndParams(req.Header, "Content-Type")
 		if value != "application/json" {
-			msg := "Content-Type header is not application/json"
-			http.Error(w, msg, http.StatusUnsupportedMediaType)
+			writeJSONError(w, http.StatusUnsupportedMediaType, "Content-Type header is not application/json")
 			return
 		}
 	}
@@ -118,44 +147,54 @@ func handleRequest(w http.ResponseWriter, req *http.Request) {
 		switch {
 		case errors.As(err, &syntaxError):
 			msg := fmt.Sprintf("Request body contains badly-formed JSON (at position %d)", syntaxError.Offset)
-			http.Error(w, msg, http.StatusBadRequest)
+			writeJSONError(w, http.StatusBadRequest, msg)
 
 		case errors.Is(err, io.ErrUnexpectedEOF):
-			msg := fmt.Sprintf("Request body contains badly-formed JSON")
-			http.Error(w, msg, http.StatusBadRequest)
+			writeJSONError(w, http.StatusBadRequest, "Request body contains badly-formed JSON")
 
 		case errors.As(err, &unmarshalTypeError):
 			msg := fmt.Sprintf("Request body contains an invalid value for the %q field (at position %d)", unmarshalTypeError.Field, unmarshalTypeError.Offset)
-			http.Error(w, msg, http.StatusBadRequest)
+			writeJSONError(w, http.StatusBadRequest, msg)
 
 		case strings.HasPrefix(err.Error(), "json: unknown field "):
 			fieldName := strings.TrimPrefix(err.Error(), "json: unknown field ")
 			msg := fmt.Sprintf("Request body contains unknown field %s", fieldName)
-			http.Error(w, msg, http.StatusBadRequest)
+			writeJSONError(w, http.StatusBadRequest, msg)
 
 		case errors.Is(err, io.EOF):
-			msg := "Request body must not be empty"
-			http.Error(w, msg, http.StatusBadRequest)
+			writeJSONError(w, http.StatusBadRequest, "Request body must not be empty")
 
 		case err.Error() == "http: request body too large":
-			msg := "Request body must not be larger than 1MB"
-			http.Error(w, msg, http.StatusRequestEntityTooLarge)
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "Request body must not be larger than 1MB")
 
 		default:
 			log.Println(err.Error())
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeJSONError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		}
 		return
 	}
 
-	var data = respData{Data: make(map[string]string)}
-
-	data.Data = checker(doms.Domains)
-	data.RequestTime = time.Since(start).String()
+	if len(doms.Domains) > maxDomains {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("a single request may submit at most %d domains", maxDomains))
+		return
+	}
 
-	js, err := json.Marshal(data)
+	ctx := req.Context()
+	force := req.URL.Query().Get("nocache") == "1"
+
+	var js []byte
+	if req.URL.Query().Get("verbose") == "1" {
+		data := verboseRespData{Data: verboseChecker(ctx, doms.Domains)}
+		data.RequestTime = time.Since(start).String()
+		js, err = json.Marshal(data)
+	} else {
+		output, meta := checker(ctx, doms.Domains, force)
+		data := respData{Data: output, Meta: meta}
+		data.RequestTime = time.Since(start).String()
+		js, err = json.Marshal(data)
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -165,6 +204,26 @@ func handleRequest(w http.ResponseWriter, req *http.Request) {
 }
 
 func main() {
-	http.HandleFunc("/api/checker", handleRequest)
-	log.Fatal(http.ListenAndServe(":8000", nil))
+	domainStore, err := newDomainStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	startScheduler(domainStore, checkPool)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/checker", instrument("/api/checker", handleRequest))
+	mux.HandleFunc("/api/domains", instrument("/api/domains", domainsHandler(domainStore)))
+	mux.HandleFunc("/api/domains/", instrument("/api/domains/", domainHandler(domainStore)))
+	mux.HandleFunc("/metrics", instrument("/metrics", metricsHandler))
+	mux.HandleFunc("/", notFoundHandler)
+
+	server := &http.Server{
+		Addr:              ":8000",
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+	log.Fatal(server.ListenAndServe())
 }
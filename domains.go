@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/SirBarksALot/starlight/internal/pool"
+	"github.com/SirBarksALot/starlight/internal/scheduler"
+	"github.com/SirBarksALot/starlight/internal/store"
+)
+
+// defaultStoreDir is where registered domains and their history are
+// persisted between restarts.
+const defaultStoreDir = "data/domains"
+
+// defaultIntervalSeconds is used when a registration request omits one.
+const defaultIntervalSeconds = 3600
+
+func newDomainStore() (*store.Store, error) {
+	return store.New(defaultStoreDir)
+}
+
+func startScheduler(st *store.Store, p *pool.Pool) {
+	s := scheduler.New(st, p)
+	go s.Run()
+}
+
+type domainRegistration struct {
+	Domain          string `json:"domain"`
+	IntervalSeconds int    `json:"interval_seconds"`
+}
+
+// domainsHandler serves POST /api/domains (register) and GET /api/domains
+// (list).
+func domainsHandler(st *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			req.Body = http.MaxBytesReader(w, req.Body, 1048576)
+			var reg domainRegistration
+			if err := json.NewDecoder(req.Body).Decode(&reg); err != nil {
+				writeJSONError(w, http.StatusBadRequest, "Request body must be valid JSON")
+				return
+			}
+			if err := store.ValidateDomain(reg.Domain); err != nil {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if reg.IntervalSeconds <= 0 {
+				reg.IntervalSeconds = defaultIntervalSeconds
+			}
+			if err := st.Register(reg.Domain, reg.IntervalSeconds); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+
+		case http.MethodGet:
+			records, err := st.List()
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(records)
+
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	}
+}
+
+// domainHandler serves DELETE /api/domains/{domain} and
+// GET /api/domains/{domain}/history.
+func domainHandler(st *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		rest := strings.TrimPrefix(req.URL.Path, "/api/domains/")
+		if rest == "" {
+			notFoundHandler(w, req)
+			return
+		}
+
+		if domain, ok := strings.CutSuffix(rest, "/history"); ok {
+			if req.Method != http.MethodGet {
+				writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+				return
+			}
+			if err := store.ValidateDomain(domain); err != nil {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			rec, err := st.Get(domain)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if rec == nil {
+				notFoundHandler(w, req)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(rec.History)
+			return
+		}
+
+		domain := rest
+		switch req.Method {
+		case http.MethodDelete:
+			if err := store.ValidateDomain(domain); err != nil {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if err := st.Remove(domain); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	}
+}
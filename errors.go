@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// errorResponse is the JSON shape returned for every API error, whether
+// surfaced as a decode failure, a 404, or a 500.
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// writeJSONError writes a JSON error body instead of the plain text
+// http.Error produces, so error responses match the success path's
+// Content-Type.
+func writeJSONError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(errorResponse{Code: code, Status: "error", Message: msg})
+}
+
+// notFoundHandler emits the same error shape as writeJSONError for routes
+// that don't match any handler.
+func notFoundHandler(w http.ResponseWriter, req *http.Request) {
+	writeJSONError(w, http.StatusNotFound, "no such route "+req.URL.Path)
+}
@@ -0,0 +1,137 @@
+// Package cache is a TTL cache of recent certcheck.Result values, so
+// repeated /api/checker calls for the same domain within the window skip
+// the network. Misses are submitted through a pool.Pool, which already
+// dedups concurrent in-flight checks for the same domain.
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SirBarksALot/starlight/internal/certcheck"
+	"github.com/SirBarksALot/starlight/internal/pool"
+)
+
+// Status describes how a Submit call was served.
+type Status string
+
+const (
+	StatusHit   Status = "HIT"
+	StatusMiss  Status = "MISS"
+	StatusStale Status = "STALE"
+)
+
+// sweepInterval is how often expired entries are purged from memory.
+const sweepInterval = time.Minute
+
+// CheckResult pairs a certcheck.Result with the cache Status that produced
+// it.
+type CheckResult struct {
+	Result certcheck.Result
+	Status Status
+}
+
+type entry struct {
+	result    certcheck.Result
+	expiresAt time.Time
+}
+
+// Cache is a TTL cache of certcheck.Result keyed by domain.
+type Cache struct {
+	ttl  time.Duration
+	pool *pool.Pool
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// New returns a Cache with the given TTL, whose misses are checked through
+// p. It starts a background goroutine that periodically evicts expired
+// entries.
+func New(ttl time.Duration, p *pool.Pool) *Cache {
+	c := &Cache{ttl: ttl, pool: p, entries: make(map[string]entry)}
+	go c.sweep()
+	return c
+}
+
+// Get returns the cached Result for domain if one exists and hasn't
+// expired.
+func (c *Cache) Get(domain string) (certcheck.Result, bool) {
+	result, state := c.lookup(domain)
+	return result, state == "fresh"
+}
+
+// Set stores result for domain, resetting its TTL.
+func (c *Cache) Set(domain string, result certcheck.Result) {
+	c.mu.Lock()
+	c.entries[domain] = entry{result: result, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+// Invalidate removes domain from the cache, if present.
+func (c *Cache) Invalidate(domain string) {
+	c.mu.Lock()
+	delete(c.entries, domain)
+	c.mu.Unlock()
+}
+
+func (c *Cache) lookup(domain string) (certcheck.Result, string) {
+	c.mu.Lock()
+	e, ok := c.entries[domain]
+	c.mu.Unlock()
+
+	if !ok {
+		return certcheck.Result{}, "absent"
+	}
+	if time.Now().After(e.expiresAt) {
+		return e.result, "stale"
+	}
+	return e.result, "fresh"
+}
+
+// Submit returns a channel carrying the cached Result for domain, or, on a
+// miss or stale entry (or when force is set), submits to the pool and
+// caches what comes back. Concurrent misses for the same domain collapse
+// into a single dial because the underlying pool.Pool.Submit dedups
+// in-flight checks.
+func (c *Cache) Submit(domain string, force bool) <-chan CheckResult {
+	out := make(chan CheckResult, 1)
+
+	state := "absent"
+	var cached certcheck.Result
+	if !force {
+		cached, state = c.lookup(domain)
+		if state == "fresh" {
+			out <- CheckResult{Result: cached, Status: StatusHit}
+			return out
+		}
+	}
+
+	status := StatusMiss
+	if state == "stale" {
+		status = StatusStale
+	}
+
+	go func() {
+		result := <-c.pool.Submit(domain)
+		c.Set(domain, result)
+		out <- CheckResult{Result: result, Status: status}
+	}()
+	return out
+}
+
+func (c *Cache) sweep() {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+		c.mu.Lock()
+		for domain, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, domain)
+			}
+		}
+		c.mu.Unlock()
+	}
+}
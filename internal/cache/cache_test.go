@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SirBarksALot/starlight/internal/certcheck"
+	"github.com/SirBarksALot/starlight/internal/pool"
+)
+
+func TestSetGetAndExpiry(t *testing.T) {
+	c := New(50*time.Millisecond, pool.New(1, nil))
+	result := certcheck.Result{Domain: "example.com"}
+
+	c.Set("example.com", result)
+
+	got, ok := c.Get("example.com")
+	if !ok {
+		t.Fatal("expected a fresh hit right after Set")
+	}
+	if got.Domain != "example.com" {
+		t.Fatalf("Domain = %q, want %q", got.Domain, "example.com")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := c.Get("example.com"); ok {
+		t.Fatal("expected entry to be expired")
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	c := New(time.Minute, pool.New(1, nil))
+	c.Set("example.com", certcheck.Result{Domain: "example.com"})
+
+	c.Invalidate("example.com")
+
+	if _, ok := c.Get("example.com"); ok {
+		t.Fatal("expected Get to miss after Invalidate")
+	}
+}
+
+func TestSubmitMissThenHit(t *testing.T) {
+	c := New(time.Minute, pool.New(1, nil))
+
+	// localhost:443 has nothing listening in the test environment, so the
+	// check fails fast instead of needing real network access.
+	const domain = "localhost"
+
+	select {
+	case first := <-c.Submit(domain, false):
+		if first.Status != StatusMiss {
+			t.Fatalf("Status = %v, want %v", first.Status, StatusMiss)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for first submit")
+	}
+
+	select {
+	case second := <-c.Submit(domain, false):
+		if second.Status != StatusHit {
+			t.Fatalf("Status = %v, want %v", second.Status, StatusHit)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for second submit")
+	}
+}
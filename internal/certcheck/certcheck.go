@@ -0,0 +1,106 @@
+// Package certcheck performs the actual TLS dial and certificate inspection
+// used by the one-shot API, the scheduler, and the worker pool.
+package certcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/SirBarksALot/starlight/internal/metrics"
+)
+
+// dialTimeout bounds how long a single TLS dial may hang, since a remote
+// host that never responds would otherwise stall a check indefinitely.
+const dialTimeout = 10 * time.Second
+
+// Result is the outcome of checking a single domain's certificate.
+type Result struct {
+	Domain    string    `json:"domain"`
+	ExpiresIn float64   `json:"expires_in_days"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dns_names"`
+	CheckedAt time.Time `json:"checked_at"`
+	Err       string    `json:"error,omitempty"`
+}
+
+// Check dials domain:443 (bounded by dialTimeout and ctx), inspects the
+// leaf certificate, and returns a Result. Errors are reported on Result.Err
+// rather than as a second return value so callers always get a CheckedAt
+// timestamp to store.
+func Check(ctx context.Context, domain string) Result {
+	start := time.Now()
+	result := Result{Domain: domain, CheckedAt: start}
+	defer func() {
+		metrics.Default.CheckDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: dialTimeout}}
+	rawConn, err := dialer.DialContext(ctx, "tcp", domain+":443")
+	if err != nil {
+		log.Print(err)
+		result.Err = err.Error()
+		reason := classifyError(err)
+		metrics.Default.CheckErrorsTotal.Inc(map[string]string{"domain": domain, "reason": reason})
+		return result
+	}
+	conn := rawConn.(*tls.Conn)
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		result.Err = "server presented no certificates"
+		metrics.Default.CheckErrorsTotal.Inc(map[string]string{"domain": domain, "reason": "unknown"})
+		return result
+	}
+	cert := certs[0]
+	result.ExpiresIn = cert.NotAfter.Sub(result.CheckedAt).Hours() / 24
+	result.Issuer = cert.Issuer.CommonName
+	result.DNSNames = cert.DNSNames
+
+	if time.Now().After(cert.NotAfter) {
+		metrics.Default.CheckErrorsTotal.Inc(map[string]string{"domain": domain, "reason": "expired"})
+	}
+	metrics.Default.CertExpiryDays.Set(map[string]string{"domain": domain, "issuer": result.Issuer}, result.ExpiresIn)
+	return result
+}
+
+// classifyError unwraps the error returned by tls.Dial into one of a small
+// set of reasons suitable as a metric label.
+func classifyError(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "dial_timeout"
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthority) {
+		return "unknown_authority"
+	}
+
+	var certInvalid x509.CertificateInvalidError
+	if errors.As(err, &certInvalid) && certInvalid.Reason == x509.Expired {
+		return "expired"
+	}
+
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &recordErr) {
+		return "handshake_failure"
+	}
+
+	return "unknown"
+}
+
+// FormatDays renders a Result the way the legacy /api/checker response does:
+// the error string if the check failed, otherwise whole days until expiry.
+func FormatDays(result Result) string {
+	if result.Err != "" {
+		return result.Err
+	}
+	return strconv.FormatFloat(result.ExpiresIn, 'f', 0, 64)
+}
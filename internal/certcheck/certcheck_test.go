@@ -0,0 +1,60 @@
+package certcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+// fakeTimeoutError is a minimal net.Error whose Timeout() is controllable,
+// since net.Error has no exported constructor.
+type fakeTimeoutError struct{ timeout bool }
+
+func (e fakeTimeoutError) Error() string   { return "fake net error" }
+func (e fakeTimeoutError) Timeout() bool   { return e.timeout }
+func (e fakeTimeoutError) Temporary() bool { return false }
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"dial timeout", fakeTimeoutError{timeout: true}, "dial_timeout"},
+		{"unknown authority", x509.UnknownAuthorityError{}, "unknown_authority"},
+		{"expired cert", x509.CertificateInvalidError{Reason: x509.Expired}, "expired"},
+		{"record header error", tls.RecordHeaderError{}, "handshake_failure"},
+		{"unrecognized error", fakeTimeoutError{timeout: false}, "unknown"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := classifyError(c.err); got != c.want {
+				t.Errorf("classifyError(%v) = %q, want %q", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestFormatDays(t *testing.T) {
+	if got := FormatDays(Result{ExpiresIn: 12.9}); got != "12" {
+		t.Errorf("FormatDays(12.9 days) = %q, want %q", got, "12")
+	}
+	if got := FormatDays(Result{Err: "dial failed"}); got != "dial failed" {
+		t.Errorf("FormatDays(errored) = %q, want %q", got, "dial failed")
+	}
+}
+
+func TestCheckReturnsErrOnUnreachableHost(t *testing.T) {
+	// localhost:443 has nothing listening in the test environment, so Check
+	// fails fast with a connection error instead of needing real network
+	// access.
+	result := Check(context.Background(), "localhost")
+	if result.Err == "" {
+		t.Fatal("expected Err to be set for an unreachable host")
+	}
+	if result.CheckedAt.IsZero() || result.CheckedAt.After(time.Now()) {
+		t.Fatal("expected CheckedAt to be set before Check returns")
+	}
+}
@@ -0,0 +1,130 @@
+package certreport
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestIsDisallowedIP(t *testing.T) {
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"169.254.169.254", true}, // cloud metadata endpoint
+		{"10.0.0.5", true},
+		{"172.16.0.5", true},
+		{"192.168.1.5", true},
+		{"0.0.0.0", true},
+		{"224.0.0.1", true},
+		{"93.184.216.34", false}, // public IP
+	}
+	for _, c := range cases {
+		got := isDisallowedIP(net.ParseIP(c.ip))
+		if got != c.want {
+			t.Errorf("isDisallowedIP(%q) = %v, want %v", c.ip, got, c.want)
+		}
+	}
+}
+
+func TestCheckPublicHTTPURL(t *testing.T) {
+	cases := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"http://ocsp.example.com/ocsp", false},
+		{"https://crl.example.com/crl", false},
+		{"ftp://example.com/crl", true},
+		{"file:///etc/passwd", true},
+		{"not a url at all :://", true},
+		{"http://", true},
+	}
+	for _, c := range cases {
+		err := checkPublicHTTPURL(c.url)
+		if (err != nil) != c.wantErr {
+			t.Errorf("checkPublicHTTPURL(%q) error = %v, wantErr %v", c.url, err, c.wantErr)
+		}
+	}
+}
+
+func TestOCSPStatusString(t *testing.T) {
+	cases := []struct {
+		status int
+		want   string
+	}{
+		{ocsp.Good, "good"},
+		{ocsp.Revoked, "revoked"},
+		{ocsp.Unknown, "unknown(2)"},
+	}
+	for _, c := range cases {
+		if got := ocspStatusString(c.status); got != c.want {
+			t.Errorf("ocspStatusString(%d) = %q, want %q", c.status, got, c.want)
+		}
+	}
+}
+
+// selfSignedPEM returns a freshly generated self-signed certificate in PEM
+// form, valid enough for LoadRootPool (which only needs to parse it).
+func selfSignedPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test-root"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestLoadRootPool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, selfSignedPEM(t), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	pool, err := LoadRootPool(path)
+	if err != nil {
+		t.Fatalf("LoadRootPool: %v", err)
+	}
+	if pool == nil {
+		t.Fatal("expected a non-nil pool")
+	}
+}
+
+func TestLoadRootPoolMissingFile(t *testing.T) {
+	if _, err := LoadRootPool(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Fatal("expected an error for a missing CA bundle file")
+	}
+}
+
+func TestLoadRootPoolInvalidPEM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "garbage.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := LoadRootPool(path); err == nil {
+		t.Fatal("expected an error for a file with no usable PEM certificates")
+	}
+}
@@ -0,0 +1,192 @@
+// Package metrics is a minimal, dependency-free Prometheus exposition
+// registry. It covers the handful of gauge/counter/histogram shapes this
+// service needs so importing prometheus/client_golang isn't required; swap
+// Registry's implementation for that client's if richer collectors become
+// necessary.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+func labelKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", k, labels[k])
+	}
+	return b.String()
+}
+
+func labelString(labels map[string]string) string {
+	key := labelKey(labels)
+	if key == "" {
+		return ""
+	}
+	return "{" + key + "}"
+}
+
+// maxLabelSets bounds how many distinct label combinations a vec will
+// track. Labels like "domain" come straight from request input, so without
+// a cap a caller could submit an unbounded stream of distinct values and
+// grow these maps forever. Once the cap is hit, new label combinations are
+// dropped; existing ones keep updating.
+const maxLabelSets = 10000
+
+// GaugeVec tracks the latest value per label combination.
+type GaugeVec struct {
+	name string
+	mu   sync.Mutex
+	vals map[string]float64
+	tags map[string]map[string]string
+}
+
+func newGaugeVec(name string) *GaugeVec {
+	return &GaugeVec{name: name, vals: make(map[string]float64), tags: make(map[string]map[string]string)}
+}
+
+// Set records value for the given label combination.
+func (g *GaugeVec) Set(labels map[string]string, value float64) {
+	key := labelKey(labels)
+	g.mu.Lock()
+	if _, exists := g.vals[key]; !exists && len(g.vals) >= maxLabelSets {
+		g.mu.Unlock()
+		return
+	}
+	g.vals[key] = value
+	g.tags[key] = labels
+	g.mu.Unlock()
+}
+
+func (g *GaugeVec) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fmt.Fprintf(w, "# TYPE %s gauge\n", g.name)
+	for key, value := range g.vals {
+		fmt.Fprintf(w, "%s%s %v\n", g.name, labelString(g.tags[key]), value)
+	}
+}
+
+// CounterVec tracks a monotonically increasing count per label combination.
+type CounterVec struct {
+	name string
+	mu   sync.Mutex
+	vals map[string]float64
+	tags map[string]map[string]string
+}
+
+func newCounterVec(name string) *CounterVec {
+	return &CounterVec{name: name, vals: make(map[string]float64), tags: make(map[string]map[string]string)}
+}
+
+// Inc increments the counter for the given label combination by 1.
+func (c *CounterVec) Inc(labels map[string]string) {
+	c.Add(labels, 1)
+}
+
+// Add increments the counter for the given label combination by delta.
+func (c *CounterVec) Add(labels map[string]string, delta float64) {
+	key := labelKey(labels)
+	c.mu.Lock()
+	if _, exists := c.vals[key]; !exists && len(c.vals) >= maxLabelSets {
+		c.mu.Unlock()
+		return
+	}
+	c.vals[key] += delta
+	c.tags[key] = labels
+	c.mu.Unlock()
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(w, "# TYPE %s counter\n", c.name)
+	for key, value := range c.vals {
+		fmt.Fprintf(w, "%s%s %v\n", c.name, labelString(c.tags[key]), value)
+	}
+}
+
+var defaultBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Histogram observes samples into cumulative buckets.
+type Histogram struct {
+	name    string
+	buckets []float64
+
+	mu     sync.Mutex
+	counts []uint64
+	sum    float64
+	total  uint64
+}
+
+func newHistogram(name string) *Histogram {
+	return &Histogram{name: name, buckets: defaultBuckets, counts: make([]uint64, len(defaultBuckets))}
+}
+
+// Observe records a single sample.
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sum += value
+	h.total++
+	for i, bucket := range h.buckets {
+		if value <= bucket {
+			h.counts[i]++
+		}
+	}
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# TYPE %s histogram\n", h.name)
+	for i, bucket := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%v\"} %d\n", h.name, bucket, h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.total)
+	fmt.Fprintf(w, "%s_sum %v\n", h.name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", h.name, h.total)
+}
+
+// Registry exposes the metrics this service records in Prometheus text
+// exposition format.
+type Registry struct {
+	CertExpiryDays    *GaugeVec
+	CheckDuration     *Histogram
+	CheckErrorsTotal  *CounterVec
+	HTTPRequestsTotal *CounterVec
+}
+
+// Default is the process-wide registry used by certcheck.Check and the
+// instrument HTTP middleware.
+var Default = NewRegistry()
+
+// NewRegistry builds an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		CertExpiryDays:    newGaugeVec("starlight_cert_expiry_days"),
+		CheckDuration:     newHistogram("starlight_cert_check_duration_seconds"),
+		CheckErrorsTotal:  newCounterVec("starlight_cert_check_errors_total"),
+		HTTPRequestsTotal: newCounterVec("starlight_http_requests_total"),
+	}
+}
+
+// Render writes every metric in Prometheus text exposition format.
+func (r *Registry) Render(w io.Writer) {
+	r.CertExpiryDays.write(w)
+	r.CheckDuration.write(w)
+	r.CheckErrorsTotal.write(w)
+	r.HTTPRequestsTotal.write(w)
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabelKeyIsOrderIndependent(t *testing.T) {
+	a := labelKey(map[string]string{"domain": "example.com", "issuer": "Let's Encrypt"})
+	b := labelKey(map[string]string{"issuer": "Let's Encrypt", "domain": "example.com"})
+	if a != b {
+		t.Fatalf("labelKey differed by map iteration order: %q vs %q", a, b)
+	}
+}
+
+func TestLabelStringEmpty(t *testing.T) {
+	if got := labelString(nil); got != "" {
+		t.Errorf("labelString(nil) = %q, want empty string", got)
+	}
+}
+
+func TestGaugeVecSetAndWrite(t *testing.T) {
+	g := newGaugeVec("starlight_cert_expiry_days")
+	g.Set(map[string]string{"domain": "example.com"}, 42)
+
+	var b strings.Builder
+	g.write(&b)
+	out := b.String()
+	if !strings.Contains(out, `starlight_cert_expiry_days{domain="example.com"} 42`) {
+		t.Errorf("write output = %q, missing expected sample", out)
+	}
+}
+
+func TestGaugeVecBoundsLabelCardinality(t *testing.T) {
+	g := newGaugeVec("test_gauge")
+	for i := 0; i < maxLabelSets+10; i++ {
+		g.Set(map[string]string{"domain": strings.Repeat("x", i+1)}, float64(i))
+	}
+	if len(g.vals) != maxLabelSets {
+		t.Fatalf("len(vals) = %d, want %d", len(g.vals), maxLabelSets)
+	}
+}
+
+func TestCounterVecIncAndAdd(t *testing.T) {
+	c := newCounterVec("starlight_cert_check_errors_total")
+	labels := map[string]string{"domain": "example.com", "reason": "dial_timeout"}
+	c.Inc(labels)
+	c.Add(labels, 2)
+
+	var b strings.Builder
+	c.write(&b)
+	out := b.String()
+	if !strings.Contains(out, `starlight_cert_check_errors_total{domain="example.com",reason="dial_timeout"} 3`) {
+		t.Errorf("write output = %q, want count of 3", out)
+	}
+}
+
+func TestHistogramObserveAndWrite(t *testing.T) {
+	h := newHistogram("starlight_cert_check_duration_seconds")
+	h.Observe(0.02)
+	h.Observe(3)
+
+	var b strings.Builder
+	h.write(&b)
+	out := b.String()
+	if !strings.Contains(out, `starlight_cert_check_duration_seconds_count 2`) {
+		t.Errorf("write output = %q, want a count of 2", out)
+	}
+	if !strings.Contains(out, `starlight_cert_check_duration_seconds_bucket{le="+Inf"} 2`) {
+		t.Errorf("write output = %q, want +Inf bucket of 2", out)
+	}
+}
+
+func TestRegistryRenderIncludesAllMetrics(t *testing.T) {
+	r := NewRegistry()
+	r.CertExpiryDays.Set(map[string]string{"domain": "example.com"}, 10)
+	r.CheckDuration.Observe(0.1)
+	r.CheckErrorsTotal.Inc(map[string]string{"domain": "example.com", "reason": "expired"})
+	r.HTTPRequestsTotal.Inc(map[string]string{"path": "/api/checker", "code": "200"})
+
+	var b strings.Builder
+	r.Render(&b)
+	out := b.String()
+	for _, name := range []string{
+		"starlight_cert_expiry_days",
+		"starlight_cert_check_duration_seconds",
+		"starlight_cert_check_errors_total",
+		"starlight_http_requests_total",
+	} {
+		if !strings.Contains(out, name) {
+			t.Errorf("Render output missing metric %q", name)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Package pool provides a bounded, long-lived worker pool for certificate
+// checks, shared by the HTTP handler and the scheduler. Concurrent callers
+// asking about the same domain while a check is in flight share the result
+// of a single dial instead of each starting their own.
+package pool
+
+import (
+	"context"
+	"crypto/x509"
+	"sync"
+
+	"github.com/SirBarksALot/starlight/internal/certcheck"
+	"github.com/SirBarksALot/starlight/internal/certreport"
+)
+
+// kind distinguishes the two dials a Pool can run, so plain checks and
+// verbose reports draw from the same worker capacity instead of each
+// getting their own independent concurrency limit.
+type kind int
+
+const (
+	kindCheck kind = iota
+	kindReport
+)
+
+type job struct {
+	domain string
+	kind   kind
+}
+
+// Pool runs a fixed number of workers pulling domains off a shared channel.
+type Pool struct {
+	jobs  chan job
+	roots *x509.CertPool
+
+	mu             sync.Mutex
+	inflight       map[string][]chan certcheck.Result
+	reportInflight map[string][]chan certreport.Report
+}
+
+// New starts a Pool with the given number of workers. roots is the CA pool
+// verbose report requests verify chains against (nil uses the system pool).
+func New(workers int, roots *x509.CertPool) *Pool {
+	p := &Pool{
+		jobs:           make(chan job, workers*2),
+		roots:          roots,
+		inflight:       make(map[string][]chan certcheck.Result),
+		reportInflight: make(map[string][]chan certreport.Report),
+	}
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *Pool) worker() {
+	for j := range p.jobs {
+		switch j.kind {
+		case kindReport:
+			p.runReport(j.domain)
+		default:
+			p.runCheck(j.domain)
+		}
+	}
+}
+
+// runCheck and runReport both run on a background context rather than any
+// one waiter's request context: the dial's own timeout bounds the check,
+// and one waiter giving up shouldn't cancel the dial for the others
+// sharing it.
+
+func (p *Pool) runCheck(domain string) {
+	result := certcheck.Check(context.Background(), domain)
+
+	p.mu.Lock()
+	waiters := p.inflight[domain]
+	delete(p.inflight, domain)
+	p.mu.Unlock()
+
+	for _, ch := range waiters {
+		ch <- result
+	}
+}
+
+func (p *Pool) runReport(domain string) {
+	report := certreport.Build(context.Background(), domain, p.roots)
+
+	p.mu.Lock()
+	waiters := p.reportInflight[domain]
+	delete(p.reportInflight, domain)
+	p.mu.Unlock()
+
+	for _, ch := range waiters {
+		ch <- report
+	}
+}
+
+// Submit enqueues domain for a plain certificate check and returns a
+// channel that receives the single result. If domain is already in flight,
+// the returned channel is added to the existing waiter list instead of
+// starting a second dial.
+func (p *Pool) Submit(domain string) <-chan certcheck.Result {
+	ch := make(chan certcheck.Result, 1)
+
+	p.mu.Lock()
+	waiters := p.inflight[domain]
+	alreadyInFlight := len(waiters) > 0
+	p.inflight[domain] = append(waiters, ch)
+	p.mu.Unlock()
+
+	if !alreadyInFlight {
+		p.jobs <- job{domain: domain, kind: kindCheck}
+	}
+	return ch
+}
+
+// SubmitReport enqueues domain for a full certreport.Report and returns a
+// channel that receives the single result, deduping concurrent callers the
+// same way Submit does for plain checks.
+func (p *Pool) SubmitReport(domain string) <-chan certreport.Report {
+	ch := make(chan certreport.Report, 1)
+
+	p.mu.Lock()
+	waiters := p.reportInflight[domain]
+	alreadyInFlight := len(waiters) > 0
+	p.reportInflight[domain] = append(waiters, ch)
+	p.mu.Unlock()
+
+	if !alreadyInFlight {
+		p.jobs <- job{domain: domain, kind: kindReport}
+	}
+	return ch
+}
@@ -0,0 +1,53 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// localhost:443 has nothing listening in the test environment, so Check
+// fails fast with a connection error instead of needing real network access.
+const testDomain = "localhost"
+
+func TestSubmitReturnsResult(t *testing.T) {
+	p := New(2, nil)
+
+	select {
+	case result := <-p.Submit(testDomain):
+		if result.Domain != testDomain {
+			t.Fatalf("Domain = %q, want %q", result.Domain, testDomain)
+		}
+		if result.Err == "" {
+			t.Fatal("expected Err to be set for an unreachable host")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for result")
+	}
+}
+
+func TestSubmitDedupesInFlight(t *testing.T) {
+	p := New(1, nil)
+
+	ch1 := p.Submit(testDomain)
+	ch2 := p.Submit(testDomain)
+
+	var r1, r2 struct {
+		checkedAt time.Time
+	}
+	select {
+	case result := <-ch1:
+		r1.checkedAt = result.CheckedAt
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for first result")
+	}
+	select {
+	case result := <-ch2:
+		r2.checkedAt = result.CheckedAt
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for second result")
+	}
+
+	if !r1.checkedAt.Equal(r2.checkedAt) {
+		t.Fatalf("expected both waiters to share a single check, got CheckedAt %v and %v", r1.checkedAt, r2.checkedAt)
+	}
+}
@@ -0,0 +1,78 @@
+// Package scheduler periodically re-checks every domain registered in a
+// store.Store, recording results through a pool.Pool so that checks
+// triggered by the schedule share in-flight dedup with API callers.
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"github.com/SirBarksALot/starlight/internal/pool"
+	"github.com/SirBarksALot/starlight/internal/store"
+)
+
+// defaultTick is how often the scheduler looks for domains that are due.
+const defaultTick = 30 * time.Second
+
+// Scheduler re-checks registered domains at their configured interval.
+type Scheduler struct {
+	store *store.Store
+	pool  *pool.Pool
+	tick  time.Duration
+	stop  chan struct{}
+}
+
+// New returns a Scheduler that reads registrations from st and checks them
+// through p.
+func New(st *store.Store, p *pool.Pool) *Scheduler {
+	return &Scheduler{store: st, pool: p, tick: defaultTick, stop: make(chan struct{})}
+}
+
+// Run blocks, checking for due domains every tick interval, until Stop is
+// called. Call it in its own goroutine.
+func (s *Scheduler) Run() {
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.checkDue()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Stop ends the scheduler's Run loop.
+func (s *Scheduler) Stop() {
+	close(s.stop)
+}
+
+func (s *Scheduler) checkDue() {
+	records, err := s.store.List()
+	if err != nil {
+		log.Printf("scheduler: listing domains: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, rec := range records {
+		interval := time.Duration(rec.IntervalSeconds) * time.Second
+		if interval <= 0 {
+			continue
+		}
+		if now.Sub(rec.LastCheck) < interval {
+			continue
+		}
+
+		rec := rec
+		ch := s.pool.Submit(rec.Domain)
+		go func() {
+			result := <-ch
+			if err := s.store.Put(rec.Domain, rec.IntervalSeconds, result); err != nil {
+				log.Printf("scheduler: storing result for %s: %v", rec.Domain, err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SirBarksALot/starlight/internal/certcheck"
+	"github.com/SirBarksALot/starlight/internal/pool"
+	"github.com/SirBarksALot/starlight/internal/store"
+)
+
+func TestCheckDueChecksRegisteredDomains(t *testing.T) {
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	// localhost:443 has nothing listening in the test environment, so the
+	// check fails fast instead of needing real network access.
+	const domain = "localhost"
+	if err := st.Register(domain, 1); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	s := New(st, pool.New(1, nil))
+	s.checkDue()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec, err := st.Get(domain)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if rec != nil && len(rec.History) == 1 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for scheduler to record a check")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCheckDueSkipsDomainsNotYetDue(t *testing.T) {
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	const domain = "localhost"
+	if err := st.Register(domain, 3600); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	result := certcheck.Result{Domain: domain, CheckedAt: time.Now()}
+	if err := st.Put(domain, 3600, result); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	s := New(st, pool.New(1, nil))
+	s.checkDue()
+
+	time.Sleep(50 * time.Millisecond)
+	rec, err := st.Get(domain)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(rec.History) != 1 {
+		t.Fatalf("len(History) = %d, want 1 (checkDue should have skipped a domain just checked)", len(rec.History))
+	}
+}
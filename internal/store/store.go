@@ -0,0 +1,184 @@
+// Package store persists registered domains and their check history to
+// disk, one JSON file per domain, so the scheduler survives restarts.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/SirBarksALot/starlight/internal/certcheck"
+)
+
+// Record is the persisted state for a single registered domain.
+type Record struct {
+	Domain          string             `json:"domain"`
+	IntervalSeconds int                `json:"interval_seconds"`
+	LastCheck       time.Time          `json:"last_check"`
+	History         []certcheck.Result `json:"history"`
+}
+
+// validDomain matches a conservative subset of valid hostnames: dot-separated
+// labels of letters, digits, and hyphens. It rejects anything that could
+// escape s.dir through filepath.Join, such as values containing "/" or "..".
+var validDomain = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// ValidateDomain reports whether domain is safe to use as a store key and
+// file name. Callers should validate user-supplied domains before calling
+// any other Store method.
+func ValidateDomain(domain string) error {
+	if len(domain) == 0 || len(domain) > 253 || !validDomain.MatchString(domain) {
+		return fmt.Errorf("store: %q is not a valid domain", domain)
+	}
+	return nil
+}
+
+// maxHistory bounds how many past results are kept per domain.
+const maxHistory = 100
+
+// Store is a disk-backed key/value store of Records keyed by domain.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New returns a Store that reads and writes JSON files under dir, creating
+// dir if it does not already exist.
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("store: creating %s: %w", dir, err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) path(domain string) string {
+	return filepath.Join(s.dir, domain+".json")
+}
+
+// Put registers domain (if new) or overwrites its interval, then appends
+// result to its history, trimming to maxHistory entries.
+func (s *Store) Put(domain string, intervalSeconds int, result certcheck.Result) error {
+	if err := ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.readLocked(domain)
+	if err != nil {
+		return err
+	}
+	if rec == nil {
+		rec = &Record{Domain: domain}
+	}
+	rec.IntervalSeconds = intervalSeconds
+	rec.LastCheck = result.CheckedAt
+	rec.History = append(rec.History, result)
+	if len(rec.History) > maxHistory {
+		rec.History = rec.History[len(rec.History)-maxHistory:]
+	}
+	return s.writeLocked(rec)
+}
+
+// Register creates a Record for domain if one doesn't already exist, or
+// updates the interval of the existing one.
+func (s *Store) Register(domain string, intervalSeconds int) error {
+	if err := ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.readLocked(domain)
+	if err != nil {
+		return err
+	}
+	if rec != nil {
+		rec.IntervalSeconds = intervalSeconds
+		return s.writeLocked(rec)
+	}
+	return s.writeLocked(&Record{Domain: domain, IntervalSeconds: intervalSeconds})
+}
+
+// Remove deletes a domain's record entirely.
+func (s *Store) Remove(domain string) error {
+	if err := ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path(domain))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Get returns the Record for domain, or nil if it isn't registered.
+func (s *Store) Get(domain string) (*Record, error) {
+	if err := ValidateDomain(domain); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLocked(domain)
+}
+
+// List returns every registered domain's Record.
+func (s *Store) List() ([]*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*Record
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		domain := entry.Name()[:len(entry.Name())-len(".json")]
+		rec, err := s.readLocked(domain)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+func (s *Store) readLocked(domain string) (*Record, error) {
+	data, err := os.ReadFile(s.path(domain))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (s *Store) writeLocked(rec *Record) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(rec.Domain), data, 0o644)
+}
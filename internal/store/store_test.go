@@ -0,0 +1,87 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SirBarksALot/starlight/internal/certcheck"
+)
+
+func TestValidateDomain(t *testing.T) {
+	valid := []string{"example.com", "sub.example.com", "a", "a-b.co"}
+	for _, d := range valid {
+		if err := ValidateDomain(d); err != nil {
+			t.Errorf("ValidateDomain(%q) = %v, want nil", d, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"../../../../tmp/pwned",
+		"/etc/passwd",
+		"..",
+		".hidden",
+		"foo/bar",
+		"foo\\bar",
+	}
+	for _, d := range invalid {
+		if err := ValidateDomain(d); err == nil {
+			t.Errorf("ValidateDomain(%q) = nil, want an error", d)
+		}
+	}
+}
+
+func TestPutGetRemove(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result := certcheck.Result{Domain: "example.com", CheckedAt: time.Now()}
+	if err := s.Put("example.com", 60, result); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	rec, err := s.Get("example.com")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if rec == nil {
+		t.Fatal("Get returned nil record")
+	}
+	if len(rec.History) != 1 {
+		t.Fatalf("len(History) = %d, want 1", len(rec.History))
+	}
+
+	if err := s.Remove("example.com"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	rec, err = s.Get("example.com")
+	if err != nil {
+		t.Fatalf("Get after Remove: %v", err)
+	}
+	if rec != nil {
+		t.Fatal("expected nil record after Remove")
+	}
+}
+
+func TestPutRejectsPathTraversal(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result := certcheck.Result{Domain: "../../../../tmp/pwned", CheckedAt: time.Now()}
+	if err := s.Put("../../../../tmp/pwned", 60, result); err == nil {
+		t.Fatal("expected Put to reject a path-traversal domain")
+	}
+	if err := s.Register("../../../../tmp/pwned", 60); err == nil {
+		t.Fatal("expected Register to reject a path-traversal domain")
+	}
+	if err := s.Remove("../../../../tmp/pwned"); err == nil {
+		t.Fatal("expected Remove to reject a path-traversal domain")
+	}
+	if _, err := s.Get("../../../../tmp/pwned"); err == nil {
+		t.Fatal("expected Get to reject a path-traversal domain")
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/SirBarksALot/starlight/internal/metrics"
+)
+
+// statusRecorder captures the status code a handler wrote so it can be
+// reported as a metric label after the handler returns.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// instrument wraps next so every request increments
+// starlight_http_requests_total{path,code}.
+func instrument(path string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, req)
+		metrics.Default.HTTPRequestsTotal.Inc(map[string]string{"path": path, "code": strconv.Itoa(rec.status)})
+	}
+}
+
+// metricsHandler serves GET /metrics in Prometheus text exposition format.
+func metricsHandler(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics.Default.Render(w)
+}
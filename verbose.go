@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/SirBarksALot/starlight/internal/certreport"
+)
+
+// verboseRespData is the ?verbose=1 response shape: a full certificate
+// report per domain instead of just days-until-expiry.
+type verboseRespData struct {
+	Data        map[string]certreport.Report
+	RequestTime string
+}
+
+// verboseChecker builds a full certreport.Report for every domain by
+// submitting each to checkPool, the same worker pool and in-flight dial
+// dedup plain /api/checker requests use, so verbose and plain requests
+// share one global dial concurrency instead of each enforcing its own.
+func verboseChecker(ctx context.Context, domains []string) map[string]certreport.Report {
+	output := make(map[string]certreport.Report)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, domain := range domains {
+		wg.Add(1)
+		ch := checkPool.SubmitReport(domain)
+		go func(domain string, ch <-chan certreport.Report) {
+			defer wg.Done()
+			select {
+			case report := <-ch:
+				mu.Lock()
+				output[domain] = report
+				mu.Unlock()
+			case <-ctx.Done():
+				mu.Lock()
+				output[domain] = certreport.Report{Domain: domain, Err: ctx.Err().Error()}
+				mu.Unlock()
+			}
+		}(domain, ch)
+	}
+	wg.Wait()
+	return output
+}